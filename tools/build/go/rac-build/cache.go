@@ -0,0 +1,363 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	cacheDirName       = "rac-clicker-build"
+	cacheManifestName  = "manifest.json"
+	cacheTrimAfterDays = 7
+)
+
+// cacheManifest records the provenance of a cached artifact alongside its
+// binary so a later run can reason about what produced it.
+type cacheManifest struct {
+	ActionID  string    `json:"action_id"`
+	Name      string    `json:"name"`
+	Version   string    `json:"version"`
+	Target    string    `json:"target,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// defaultCacheDir returns ~/.cache/rac-clicker-build, the same base the rest
+// of the toolchain would use for user-scoped state.
+func defaultCacheDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve home directory: %w", err)
+	}
+	return filepath.Join(home, ".cache", cacheDirName), nil
+}
+
+func resolveCacheDir(override string) (string, error) {
+	if override != "" {
+		return filepath.Abs(override)
+	}
+	return defaultCacheDir()
+}
+
+// buildTarget builds (or restores from cache) the binary for target and
+// copies it to the output directory, returning the final destination path.
+// An empty target means a plain single-host build.
+func buildTarget(ctx context.Context, buildCtx *BuildContext, target string) (string, error) {
+	destFilename := destFilenameForTarget(buildCtx.Config.Package.Name, buildCtx.Config.Package.Version, target)
+
+	var cacheDir, actionID string
+
+	if !buildCtx.Options.NoCache {
+		dir, err := resolveCacheDir(buildCtx.Options.CacheDir)
+		if err != nil {
+			return "", fmt.Errorf("resolve cache dir: %w", err)
+		}
+
+		id, err := computeActionID(buildCtx, target)
+		if err != nil {
+			return "", fmt.Errorf("compute action id: %w", err)
+		}
+
+		cacheDir, actionID = dir, id
+
+		if cachedPath, ok := lookupCache(cacheDir, actionID, destFilename); ok {
+			log.Printf("Cache hit for %s (action %s)", destFilename, actionID[:12])
+
+			if !buildCtx.Options.DryRun {
+				touchCacheEntry(cacheDir, actionID)
+			}
+
+			destPath, err := copyCachedBinaryToDestination(buildCtx, cachedPath, target)
+			if err != nil {
+				return "", err
+			}
+
+			// Recorded even under --dry-run: runSignRelease needs the planned
+			// destination paths to trace the checksum/signing commands it would run.
+			buildCtx.recordArtifact(destPath)
+
+			return destPath, nil
+		}
+	}
+
+	if err := executeCargoBuild(ctx, buildCtx, target); err != nil {
+		return "", fmt.Errorf("cargo build: %w", err)
+	}
+
+	builtBinary, err := locateBuiltBinary(buildCtx, target)
+	if err != nil {
+		return "", fmt.Errorf("binary location: %w", err)
+	}
+
+	destPath, err := copyBinaryToDestination(buildCtx, builtBinary, target)
+	if err != nil {
+		return "", fmt.Errorf("binary copy: %w", err)
+	}
+
+	if cacheDir != "" && !buildCtx.Options.DryRun {
+		manifest := cacheManifest{
+			ActionID:  actionID,
+			Name:      buildCtx.Config.Package.Name,
+			Version:   buildCtx.Config.Package.Version,
+			Target:    target,
+			CreatedAt: time.Now(),
+		}
+
+		if err := storeCache(buildCtx, cacheDir, actionID, destFilename, builtBinary, manifest); err != nil {
+			log.Printf("Warning: failed to populate build cache: %v", err)
+		}
+	}
+
+	buildCtx.recordArtifact(destPath)
+
+	return destPath, nil
+}
+
+// computeActionID hashes every input that determines the shape of a release
+// binary, so a build can be skipped whenever none of them have changed.
+func computeActionID(buildCtx *BuildContext, target string) (string, error) {
+	h := sha256.New()
+
+	if err := hashFile(h, filepath.Join(buildCtx.ProjectDir, cargoTomlFilename)); err != nil {
+		return "", err
+	}
+
+	lockPath := filepath.Join(buildCtx.ProjectDir, "Cargo.lock")
+	if fileExists(lockPath) {
+		if err := hashFile(h, lockPath); err != nil {
+			return "", err
+		}
+	}
+
+	if err := hashTree(h, filepath.Join(buildCtx.ProjectDir, "src")); err != nil {
+		return "", err
+	}
+
+	buildRsPath := filepath.Join(buildCtx.ProjectDir, "build.rs")
+	if fileExists(buildRsPath) {
+		if err := hashFile(h, buildRsPath); err != nil {
+			return "", err
+		}
+	}
+
+	fmt.Fprintf(h, "cargo-version:%s\n", buildCtx.CargoVersion)
+	fmt.Fprintf(h, "goos:%s\n", runtime.GOOS)
+	fmt.Fprintf(h, "goarch:%s\n", runtime.GOARCH)
+	fmt.Fprintf(h, "target:%s\n", target)
+	fmt.Fprintf(h, "cargo-args:%s\n", strings.Join(cargoBuildArgs(target), " "))
+	fmt.Fprintf(h, "tool-version:%s\n", toolVersion)
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func hashFile(h io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("hash %s: %w", path, err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	if _, err := io.Copy(h, f); err != nil {
+		return fmt.Errorf("hash %s: %w", path, err)
+	}
+	return nil
+}
+
+// hashTree walks dir in sorted order and feeds each regular file's relative
+// path and contents into h, so renames and edits both change the digest.
+func hashTree(h io.Writer, dir string) error {
+	if !fileExists(dir) {
+		return nil
+	}
+
+	var paths []string
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		paths = append(paths, path)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("walk %s: %w", dir, err)
+	}
+
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return fmt.Errorf("relativize %s: %w", path, err)
+		}
+
+		fmt.Fprintf(h, "file:%s\n", filepath.ToSlash(rel))
+
+		if err := hashFile(h, path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func cacheEntryDir(cacheDir, actionID string) string {
+	return filepath.Join(cacheDir, actionID)
+}
+
+func lookupCache(cacheDir, actionID, destFilename string) (string, bool) {
+	path := filepath.Join(cacheEntryDir(cacheDir, actionID), destFilename)
+	if fileExists(path) {
+		return path, true
+	}
+	return "", false
+}
+
+// touchCacheEntry bumps a cache entry's manifest mtime to now, marking it as
+// recently used so trimBuildCache won't reap an entry that's still in
+// active rotation just because it was first populated outside the trim
+// window.
+func touchCacheEntry(cacheDir, actionID string) {
+	manifestPath := filepath.Join(cacheEntryDir(cacheDir, actionID), cacheManifestName)
+
+	now := time.Now()
+	if err := os.Chtimes(manifestPath, now, now); err != nil {
+		log.Printf("Warning: failed to refresh cache entry timestamp: %v", err)
+	}
+}
+
+func storeCache(buildCtx *BuildContext, cacheDir, actionID, destFilename, builtBinary string, manifest cacheManifest) error {
+	entryDir := cacheEntryDir(cacheDir, actionID)
+
+	if err := os.MkdirAll(entryDir, 0755); err != nil {
+		return fmt.Errorf("create cache entry: %w", err)
+	}
+
+	if err := buildCtx.Shell.CopyFile(builtBinary, filepath.Join(entryDir, destFilename)); err != nil {
+		return fmt.Errorf("store cache artifact: %w", err)
+	}
+
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal cache manifest: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(entryDir, cacheManifestName), manifestBytes, 0644); err != nil {
+		return fmt.Errorf("write cache manifest: %w", err)
+	}
+
+	return nil
+}
+
+// copyCachedBinaryToDestination restores a cached artifact into the output
+// directory, hardlinking when possible and falling back to a copy across
+// filesystem boundaries.
+func copyCachedBinaryToDestination(buildCtx *BuildContext, cachedPath, target string) (string, error) {
+	outputDir := releaseTargetDir
+	if buildCtx.Options.OutputDir != "" {
+		outputDir = buildCtx.Options.OutputDir
+	}
+
+	absOutputDir, err := filepath.Abs(outputDir)
+	if err != nil {
+		return "", fmt.Errorf("resolve output directory: %w", err)
+	}
+
+	if !buildCtx.Options.DryRun {
+		if err := os.MkdirAll(absOutputDir, 0755); err != nil {
+			return "", fmt.Errorf("create output directory: %w", err)
+		}
+	}
+
+	destFilename := destFilenameForTarget(buildCtx.Config.Package.Name, buildCtx.Config.Package.Version, target)
+	destPath := filepath.Join(absOutputDir, destFilename)
+
+	buildCtx.Shell.ShowCmd("", "cp %s %s", shellQuote(cachedPath), shellQuote(destPath))
+
+	if buildCtx.Options.DryRun {
+		return destPath, nil
+	}
+
+	if err := restoreFromCache(cachedPath, destPath); err != nil {
+		return "", fmt.Errorf("restore cached binary: %w", err)
+	}
+
+	fileInfo, err := os.Stat(destPath)
+	if err != nil {
+		return "", fmt.Errorf("stat destination file: %w", err)
+	}
+
+	log.Printf("✓ SUCCESS (cached): %s (%d bytes)", destPath, fileInfo.Size())
+	emitBinaryCopiedEvent(buildCtx.Options.JSON, cachedPath, destPath)
+
+	return destPath, nil
+}
+
+func restoreFromCache(source, destination string) error {
+	tempPath := destination + tempFileSuffix
+
+	if err := os.Link(source, tempPath); err == nil {
+		if err := os.Rename(tempPath, destination); err != nil {
+			return fmt.Errorf("atomic rename: %w", err)
+		}
+		return nil
+	}
+
+	return performFileCopy(source, destination)
+}
+
+// trimBuildCache deletes cache entries whose manifest hasn't been touched
+// (created or hit) within the trim window.
+func trimBuildCache(buildCtx *BuildContext) error {
+	cacheDir, err := resolveCacheDir(buildCtx.Options.CacheDir)
+	if err != nil {
+		return fmt.Errorf("resolve cache dir: %w", err)
+	}
+
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("read cache dir: %w", err)
+	}
+
+	cutoff := time.Now().Add(-cacheTrimAfterDays * 24 * time.Hour)
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		manifestPath := filepath.Join(cacheDir, entry.Name(), cacheManifestName)
+
+		info, err := os.Stat(manifestPath)
+		if err != nil {
+			continue
+		}
+
+		if info.ModTime().Before(cutoff) {
+			if err := buildCtx.Shell.RemoveAll(filepath.Join(cacheDir, entry.Name())); err != nil {
+				log.Printf("Warning: failed to trim cache entry %s: %v", entry.Name(), err)
+			}
+		}
+	}
+
+	return nil
+}
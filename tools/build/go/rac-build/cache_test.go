@@ -0,0 +1,145 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestBuildContext(projectDir string) *BuildContext {
+	return &BuildContext{
+		ProjectDir:   projectDir,
+		Config:       &CargoConfig{Package: PackageInfo{Name: "rac-clicker", Version: "0.1.0"}},
+		CargoVersion: "cargo 1.75.0",
+		Shell:        newShell(projectDir, false, false, false),
+	}
+}
+
+func writeTestFile(t *testing.T, path, contents string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("mkdir %s: %v", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func TestComputeActionID_StableAcrossCalls(t *testing.T) {
+	projectDir := t.TempDir()
+	writeTestFile(t, filepath.Join(projectDir, "Cargo.toml"), "[package]\nname = \"rac-clicker\"\nversion = \"0.1.0\"\n")
+	writeTestFile(t, filepath.Join(projectDir, "src", "main.rs"), "fn main() {}\n")
+
+	buildCtx := newTestBuildContext(projectDir)
+
+	first, err := computeActionID(buildCtx, "")
+	if err != nil {
+		t.Fatalf("computeActionID: %v", err)
+	}
+
+	second, err := computeActionID(buildCtx, "")
+	if err != nil {
+		t.Fatalf("computeActionID: %v", err)
+	}
+
+	if first != second {
+		t.Fatalf("action ID changed with no input change: %s != %s", first, second)
+	}
+}
+
+func TestComputeActionID_ChangesWithSource(t *testing.T) {
+	projectDir := t.TempDir()
+	writeTestFile(t, filepath.Join(projectDir, "Cargo.toml"), "[package]\nname = \"rac-clicker\"\nversion = \"0.1.0\"\n")
+	writeTestFile(t, filepath.Join(projectDir, "src", "main.rs"), "fn main() {}\n")
+
+	buildCtx := newTestBuildContext(projectDir)
+
+	before, err := computeActionID(buildCtx, "")
+	if err != nil {
+		t.Fatalf("computeActionID: %v", err)
+	}
+
+	writeTestFile(t, filepath.Join(projectDir, "src", "main.rs"), "fn main() { println!(\"hi\"); }\n")
+
+	after, err := computeActionID(buildCtx, "")
+	if err != nil {
+		t.Fatalf("computeActionID: %v", err)
+	}
+
+	if before == after {
+		t.Fatalf("action ID did not change after editing src/main.rs")
+	}
+}
+
+func TestStoreAndLookupCache_ByteIdenticalRestore(t *testing.T) {
+	projectDir := t.TempDir()
+	cacheDir := t.TempDir()
+
+	builtBinary := filepath.Join(projectDir, "built-binary")
+	writeTestFile(t, builtBinary, "pretend-binary-contents")
+
+	buildCtx := newTestBuildContext(projectDir)
+
+	manifest := cacheManifest{ActionID: "deadbeef", Name: "rac-clicker", Version: "0.1.0"}
+	if err := storeCache(buildCtx, cacheDir, "deadbeef", "rac-clicker-v0.1.0", builtBinary, manifest); err != nil {
+		t.Fatalf("storeCache: %v", err)
+	}
+
+	cachedPath, ok := lookupCache(cacheDir, "deadbeef", "rac-clicker-v0.1.0")
+	if !ok {
+		t.Fatalf("lookupCache: expected a hit after storeCache")
+	}
+
+	got, err := os.ReadFile(cachedPath)
+	if err != nil {
+		t.Fatalf("read cached artifact: %v", err)
+	}
+
+	want, err := os.ReadFile(builtBinary)
+	if err != nil {
+		t.Fatalf("read source binary: %v", err)
+	}
+
+	if string(got) != string(want) {
+		t.Fatalf("cached artifact is not byte-identical to the built binary")
+	}
+
+	if _, ok := lookupCache(cacheDir, "not-the-right-action-id", "rac-clicker-v0.1.0"); ok {
+		t.Fatalf("lookupCache: expected a miss for an unrelated action ID")
+	}
+}
+
+func TestTouchCacheEntry_RefreshesManifestMtime(t *testing.T) {
+	projectDir := t.TempDir()
+	cacheDir := t.TempDir()
+
+	builtBinary := filepath.Join(projectDir, "built-binary")
+	writeTestFile(t, builtBinary, "pretend-binary-contents")
+
+	buildCtx := newTestBuildContext(projectDir)
+
+	manifest := cacheManifest{ActionID: "deadbeef", Name: "rac-clicker", Version: "0.1.0"}
+	if err := storeCache(buildCtx, cacheDir, "deadbeef", "rac-clicker-v0.1.0", builtBinary, manifest); err != nil {
+		t.Fatalf("storeCache: %v", err)
+	}
+
+	manifestPath := filepath.Join(cacheEntryDir(cacheDir, "deadbeef"), cacheManifestName)
+
+	stale := time.Now().Add(-8 * 24 * time.Hour)
+	if err := os.Chtimes(manifestPath, stale, stale); err != nil {
+		t.Fatalf("Chtimes: %v", err)
+	}
+
+	touchCacheEntry(cacheDir, "deadbeef")
+
+	info, err := os.Stat(manifestPath)
+	if err != nil {
+		t.Fatalf("stat manifest: %v", err)
+	}
+
+	if !info.ModTime().After(stale) {
+		t.Fatalf("touchCacheEntry did not refresh the manifest mtime")
+	}
+}
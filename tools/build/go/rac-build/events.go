@@ -0,0 +1,140 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+var eventsMu sync.Mutex
+
+type projectDiscoveredEvent struct {
+	Event string `json:"Event"`
+	Dir   string `json:"Dir"`
+}
+
+type configLoadedEvent struct {
+	Event   string `json:"Event"`
+	Name    string `json:"Name"`
+	Version string `json:"Version"`
+}
+
+// Target identifies which cross-compile target a CargoStart/CargoOutput/
+// CargoEnd event belongs to. It's empty for the single-target build path,
+// where there's only ever one cargo invocation in flight to attribute output
+// to. Matrix builds run one cargo invocation per target concurrently, so
+// without this a consumer has no way to tell which in-flight build a given
+// CargoOutput/CargoEnd line came from.
+type cargoStartEvent struct {
+	Event  string   `json:"Event"`
+	Target string   `json:"Target,omitempty"`
+	Args   []string `json:"Args"`
+}
+
+type cargoOutputEvent struct {
+	Event  string `json:"Event"`
+	Target string `json:"Target,omitempty"`
+	Stream string `json:"Stream"`
+	Data   string `json:"Data"`
+}
+
+type cargoEndEvent struct {
+	Event      string `json:"Event"`
+	Target     string `json:"Target,omitempty"`
+	ExitCode   int    `json:"ExitCode"`
+	DurationMs int64  `json:"DurationMs"`
+}
+
+type binaryCopiedEvent struct {
+	Event     string `json:"Event"`
+	Source    string `json:"Source"`
+	Dest      string `json:"Dest"`
+	SizeBytes int64  `json:"SizeBytes"`
+	SHA256    string `json:"SHA256"`
+}
+
+type doneEvent struct {
+	Event   string `json:"Event"`
+	Success bool   `json:"Success"`
+}
+
+type errorEvent struct {
+	Event   string `json:"Event"`
+	Phase   string `json:"Phase"`
+	Message string `json:"Message"`
+}
+
+// emitEvent writes v to stdout as a single NDJSON line when enabled is true,
+// so CI wrappers and editors can consume the build pipeline without
+// screen-scraping human-readable logs.
+func emitEvent(enabled bool, v interface{}) {
+	if !enabled {
+		return
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to marshal event: %v\n", err)
+		return
+	}
+
+	eventsMu.Lock()
+	defer eventsMu.Unlock()
+
+	fmt.Fprintln(os.Stdout, string(data))
+}
+
+func emitErrorEvent(enabled bool, phase string, err error) {
+	emitEvent(enabled, errorEvent{Event: "Error", Phase: phase, Message: err.Error()})
+}
+
+// emitBinaryCopiedEvent hashes the artifact at dest and emits a
+// BinaryCopied event describing it. Failures to stat/hash are reported to
+// stderr rather than aborting the build, since the copy itself already
+// succeeded.
+func emitBinaryCopiedEvent(enabled bool, source, dest string) {
+	if !enabled {
+		return
+	}
+
+	info, err := os.Stat(dest)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to stat %s for BinaryCopied event: %v\n", dest, err)
+		return
+	}
+
+	sum, err := sha256OfFile(dest)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to hash %s for BinaryCopied event: %v\n", dest, err)
+		return
+	}
+
+	emitEvent(true, binaryCopiedEvent{
+		Event:     "BinaryCopied",
+		Source:    source,
+		Dest:      dest,
+		SizeBytes: info.Size(),
+		SHA256:    sum,
+	})
+}
+
+func sha256OfFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
 
 	"github.com/pelletier/go-toml/v2"
 )
@@ -20,6 +21,7 @@ const (
 	cargoTomlFilename = "Cargo.toml"
 	releaseTargetDir  = "target/release"
 	tempFileSuffix    = ".tmp"
+	toolVersion       = "0.1.0"
 )
 
 type CargoConfig struct {
@@ -37,13 +39,32 @@ type BuildOptions struct {
 	OutputDir string
 	CargoPath string
 	DryRun    bool
+	Targets   []string
+	TargetAll bool
+	Jobs      int
+	NoCache   bool
+	CacheDir  string
+	Trace     bool
+	JSON      bool
+
+	SignMinisignKey      string
+	SignCosignKeyRef     string
+	SignPassphraseFile   string
+	Verify               bool
+	VerifyMinisignPubkey string
+	VerifyCosignPubkey   string
 }
 
 type BuildContext struct {
-	Options    BuildOptions
-	ProjectDir string
-	Config     *CargoConfig
-	CargoBin   string
+	Options      BuildOptions
+	ProjectDir   string
+	Config       *CargoConfig
+	CargoBin     string
+	CargoVersion string
+	Shell        *Shell
+
+	artifactsMu sync.Mutex
+	Artifacts   []string
 }
 
 func main() {
@@ -59,68 +80,144 @@ func main() {
 func parseCLIFlags() BuildOptions {
 	opts := BuildOptions{}
 
+	var targets string
+
 	flag.BoolVar(&opts.Verbose, "v", false, "Enable verbose output")
 	flag.BoolVar(&opts.Verbose, "verbose", false, "Enable verbose output")
 	flag.BoolVar(&opts.Clean, "clean", false, "Clean before building")
 	flag.StringVar(&opts.OutputDir, "o", "", "Custom output directory (default: target/release)")
 	flag.StringVar(&opts.CargoPath, "cargo-path", "", "Explicit cargo binary path")
 	flag.BoolVar(&opts.DryRun, "dry-run", false, "Show planned actions without executing")
+	flag.StringVar(&targets, "target", "", "Comma-separated Rust target triples to cross-compile for")
+	flag.BoolVar(&opts.TargetAll, "target-all", false, "Cross-compile for all supported target triples")
+	flag.IntVar(&opts.Jobs, "j", runtime.NumCPU(), "Number of concurrent cross-compilation workers")
+	flag.BoolVar(&opts.NoCache, "no-cache", false, "Bypass the build cache")
+	flag.StringVar(&opts.CacheDir, "cache-dir", "", "Override the build cache directory (default: ~/.cache/rac-clicker-build)")
+	flag.BoolVar(&opts.Trace, "x", false, "Print shell-equivalent commands before running them")
+	flag.BoolVar(&opts.JSON, "json", false, "Emit NDJSON build events to stdout instead of human-readable logs")
+	flag.StringVar(&opts.SignMinisignKey, "sign-minisign", "", "Sign release artifacts with minisign using this secret key file")
+	flag.StringVar(&opts.SignCosignKeyRef, "sign-cosign", "", "Sign release artifacts with cosign using this key reference")
+	flag.StringVar(&opts.SignPassphraseFile, "sign-passphrase-file", "", "File containing the signing key passphrase, for non-interactive CI use")
+	flag.BoolVar(&opts.Verify, "verify", false, "Verify previously produced release artifacts instead of building")
+	flag.StringVar(&opts.VerifyMinisignPubkey, "verify-minisign-pubkey", "", "minisign public key file checked by --verify")
+	flag.StringVar(&opts.VerifyCosignPubkey, "verify-cosign-pubkey", "", "cosign public key reference checked by --verify")
 
 	flag.Parse()
 
+	if targets != "" {
+		for _, t := range strings.Split(targets, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				opts.Targets = append(opts.Targets, t)
+			}
+		}
+	}
+
 	return opts
 }
 
 func execute(opts BuildOptions) error {
 	ctx := context.Background()
 
+	if opts.JSON {
+		log.SetOutput(io.Discard)
+	}
+
 	projectDir, err := locateProjectRoot()
 	if err != nil {
+		emitErrorEvent(opts.JSON, "project discovery", err)
 		return fmt.Errorf("project discovery: %w", err)
 	}
 
 	log.Printf("Project root: %s", projectDir)
+	emitEvent(opts.JSON, projectDiscoveredEvent{Event: "ProjectDiscovered", Dir: projectDir})
 
 	config, err := loadCargoConfig(projectDir)
 	if err != nil {
+		emitErrorEvent(opts.JSON, "config loading", err)
 		return fmt.Errorf("config loading: %w", err)
 	}
 
 	if err := config.Validate(); err != nil {
+		emitErrorEvent(opts.JSON, "config validation", err)
 		return fmt.Errorf("config validation: %w", err)
 	}
 
 	if err := validateVersionString(config.Package.Version); err != nil {
+		emitErrorEvent(opts.JSON, "version validation", err)
 		return fmt.Errorf("version validation: %w", err)
 	}
 
+	emitEvent(opts.JSON, configLoadedEvent{Event: "ConfigLoaded", Name: config.Package.Name, Version: config.Package.Version})
+
+	if opts.Verify {
+		if opts.DryRun {
+			err := errors.New("--verify is a trust check and must actually run; it cannot be combined with --dry-run")
+			emitErrorEvent(opts.JSON, "release verification", err)
+			return fmt.Errorf("release verification: %w", err)
+		}
+
+		buildCtx := &BuildContext{
+			Options:    opts,
+			ProjectDir: projectDir,
+			Config:     config,
+			Shell:      newShell(projectDir, opts.Trace, opts.DryRun, opts.JSON),
+		}
+
+		if err := runVerifyRelease(buildCtx); err != nil {
+			emitErrorEvent(opts.JSON, "release verification", err)
+			return fmt.Errorf("release verification: %w", err)
+		}
+
+		emitEvent(opts.JSON, doneEvent{Event: "Done", Success: true})
+		return nil
+	}
+
 	cargoBin, err := resolveCargoBinary(opts.CargoPath)
 	if err != nil {
+		emitErrorEvent(opts.JSON, "cargo resolution", err)
 		return fmt.Errorf("cargo resolution: %w", err)
 	}
 
-	if err := validateCargoBinary(cargoBin); err != nil {
+	cargoVersion, err := validateCargoBinary(cargoBin)
+	if err != nil {
+		emitErrorEvent(opts.JSON, "cargo validation", err)
 		return fmt.Errorf("cargo validation: %w", err)
 	}
 
 	buildCtx := &BuildContext{
-		Options:    opts,
-		ProjectDir: projectDir,
-		Config:     config,
-		CargoBin:   cargoBin,
+		Options:      opts,
+		ProjectDir:   projectDir,
+		Config:       config,
+		CargoBin:     cargoBin,
+		CargoVersion: cargoVersion,
+		Shell:        newShell(projectDir, opts.Trace, opts.DryRun, opts.JSON),
 	}
 
 	log.Printf("Building %s v%s", config.Package.Name, config.Package.Version)
 
 	if err := runBuildPipeline(ctx, buildCtx); err != nil {
+		emitErrorEvent(opts.JSON, "build pipeline", err)
 		return fmt.Errorf("build pipeline: %w", err)
 	}
 
+	if !buildCtx.Options.NoCache && !buildCtx.Options.DryRun {
+		if err := trimBuildCache(buildCtx); err != nil {
+			log.Printf("Warning: failed to trim build cache: %v", err)
+		}
+	}
+
+	if err := runSignRelease(buildCtx); err != nil {
+		emitErrorEvent(opts.JSON, "release signing", err)
+		return fmt.Errorf("release signing: %w", err)
+	}
+
+	emitEvent(opts.JSON, doneEvent{Event: "Done", Success: true})
+
 	return nil
 }
 
 func runBuildPipeline(ctx context.Context, buildCtx *BuildContext) error {
-	if err := os.Chdir(buildCtx.ProjectDir); err != nil {
+	if err := buildCtx.Shell.Chdir(buildCtx.ProjectDir); err != nil {
 		return fmt.Errorf("chdir to project: %w", err)
 	}
 
@@ -130,20 +227,19 @@ func runBuildPipeline(ctx context.Context, buildCtx *BuildContext) error {
 		}
 	}
 
-	if err := executeCargoBuild(ctx, buildCtx); err != nil {
-		return fmt.Errorf("cargo build: %w", err)
+	targets := resolveTargets(buildCtx.Options)
+	if len(targets) == 0 {
+		return runSingleHostBuild(ctx, buildCtx)
 	}
 
-	builtBinary, err := locateBuiltBinary(buildCtx)
-	if err != nil {
-		return fmt.Errorf("binary location: %w", err)
-	}
+	log.Printf("Building %d target(s): %s", len(targets), strings.Join(targets, ", "))
 
-	if err := copyBinaryToDestination(buildCtx, builtBinary); err != nil {
-		return fmt.Errorf("binary copy: %w", err)
-	}
+	return runMatrixBuild(ctx, buildCtx, targets)
+}
 
-	return nil
+func runSingleHostBuild(ctx context.Context, buildCtx *BuildContext) error {
+	_, err := buildTarget(ctx, buildCtx, "")
+	return err
 }
 
 func locateProjectRoot() (string, error) {
@@ -219,17 +315,19 @@ func validateVersionString(version string) error {
 	return nil
 }
 
-func validateCargoBinary(cargo string) error {
+func validateCargoBinary(cargo string) (string, error) {
 	cmd := exec.Command(cargo, "--version")
 	out, err := cmd.Output()
 	if err != nil {
-		return errors.New("unable to execute cargo --version")
+		return "", errors.New("unable to execute cargo --version")
 	}
 
-	if !strings.HasPrefix(string(out), "cargo ") {
-		return errors.New("invalid cargo binary")
+	version := strings.TrimSpace(string(out))
+
+	if !strings.HasPrefix(version, "cargo ") {
+		return "", errors.New("invalid cargo binary")
 	}
-	return nil
+	return version, nil
 }
 
 func resolveCargoBinary(explicit string) (string, error) {
@@ -242,57 +340,42 @@ func resolveCargoBinary(explicit string) (string, error) {
 func executeCargoClean(ctx context.Context, buildCtx *BuildContext) error {
 	log.Println("Running: cargo clean")
 
-	if buildCtx.Options.DryRun {
-		log.Println("[DRY-RUN] Skipping cargo clean")
-		return nil
+	if err := buildCtx.Shell.Run(ctx, buildCtx.ProjectDir, buildCtx.Options.Verbose, "", buildCtx.CargoBin, "clean"); err != nil {
+		return fmt.Errorf("cargo command failed: %w", err)
 	}
 
-	return runCargoCommand(ctx, buildCtx, "clean")
+	return nil
 }
 
-func executeCargoBuild(ctx context.Context, buildCtx *BuildContext) error {
-	log.Println("Running: cargo build --release")
-
-	if buildCtx.Options.DryRun {
-		log.Println("[DRY-RUN] Skipping cargo build")
-		return nil
+func cargoBuildArgs(target string) []string {
+	args := []string{"build", "--release"}
+	if target != "" {
+		args = append(args, "--target", target)
 	}
-
-	return runCargoCommand(ctx, buildCtx, "build", "--release")
+	return args
 }
 
-func runCargoCommand(ctx context.Context, buildCtx *BuildContext, args ...string) error {
-	cmd := exec.CommandContext(ctx, buildCtx.CargoBin, args...)
-	cmd.Dir = buildCtx.ProjectDir
-
-	if buildCtx.Options.Verbose {
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
-	} else {
-		capturedOutput := strings.Builder{}
-		cmd.Stdout = &capturedOutput
-		cmd.Stderr = &capturedOutput
-
-		if err := cmd.Run(); err != nil {
-			if _, writeErr := fmt.Fprintln(os.Stderr, capturedOutput.String()); writeErr != nil {
-				log.Printf("Warning: failed to write error output: %v", writeErr)
-			}
-			return fmt.Errorf("cargo command failed: %w", err)
-		}
+func executeCargoBuild(ctx context.Context, buildCtx *BuildContext, target string) error {
+	args := cargoBuildArgs(target)
 
-		return nil
-	}
+	log.Printf("Running: cargo %s", strings.Join(args, " "))
 
-	if err := cmd.Run(); err != nil {
+	if err := buildCtx.Shell.Run(ctx, buildCtx.ProjectDir, buildCtx.Options.Verbose, target, buildCtx.CargoBin, args...); err != nil {
 		return fmt.Errorf("cargo command failed: %w", err)
 	}
 
 	return nil
 }
 
-func locateBuiltBinary(buildCtx *BuildContext) (string, error) {
-	binaryName := buildCtx.Config.Package.Name + getPlatformExecutableExtension()
-	binaryPath := filepath.Join(buildCtx.ProjectDir, releaseTargetDir, binaryName)
+func locateBuiltBinary(buildCtx *BuildContext, target string) (string, error) {
+	binaryName := buildCtx.Config.Package.Name + executableExtensionForTarget(target)
+
+	targetDir := releaseTargetDir
+	if target != "" {
+		targetDir = filepath.Join("target", target, "release")
+	}
+
+	binaryPath := filepath.Join(buildCtx.ProjectDir, targetDir, binaryName)
 
 	if !fileExists(binaryPath) {
 		return "", fmt.Errorf("binary not found at expected location: %s", binaryPath)
@@ -301,7 +384,7 @@ func locateBuiltBinary(buildCtx *BuildContext) (string, error) {
 	return binaryPath, nil
 }
 
-func copyBinaryToDestination(buildCtx *BuildContext, sourceBinary string) error {
+func copyBinaryToDestination(buildCtx *BuildContext, sourceBinary, target string) (string, error) {
 	outputDir := releaseTargetDir
 	if buildCtx.Options.OutputDir != "" {
 		outputDir = buildCtx.Options.OutputDir
@@ -309,39 +392,35 @@ func copyBinaryToDestination(buildCtx *BuildContext, sourceBinary string) error
 
 	absOutputDir, err := filepath.Abs(outputDir)
 	if err != nil {
-		return fmt.Errorf("resolve output directory: %w", err)
+		return "", fmt.Errorf("resolve output directory: %w", err)
 	}
 
 	if !buildCtx.Options.DryRun {
 		if err := os.MkdirAll(absOutputDir, 0755); err != nil {
-			return fmt.Errorf("create output directory: %w", err)
+			return "", fmt.Errorf("create output directory: %w", err)
 		}
 	}
 
-	destFilename := fmt.Sprintf("%s-v%s%s",
-		buildCtx.Config.Package.Name,
-		buildCtx.Config.Package.Version,
-		getPlatformExecutableExtension())
-
+	destFilename := destFilenameForTarget(buildCtx.Config.Package.Name, buildCtx.Config.Package.Version, target)
 	destPath := filepath.Join(absOutputDir, destFilename)
 
-	if buildCtx.Options.DryRun {
-		log.Printf("[DRY-RUN] Would copy: %s -> %s", sourceBinary, destPath)
-		return nil
+	if err := buildCtx.Shell.CopyFile(sourceBinary, destPath); err != nil {
+		return "", fmt.Errorf("copy binary: %w", err)
 	}
 
-	if err := performFileCopy(sourceBinary, destPath); err != nil {
-		return fmt.Errorf("copy binary: %w", err)
+	if buildCtx.Options.DryRun {
+		return destPath, nil
 	}
 
 	fileInfo, err := os.Stat(destPath)
 	if err != nil {
-		return fmt.Errorf("stat destination file: %w", err)
+		return "", fmt.Errorf("stat destination file: %w", err)
 	}
 
 	log.Printf("✓ SUCCESS: %s (%d bytes)", destPath, fileInfo.Size())
+	emitBinaryCopiedEvent(buildCtx.Options.JSON, sourceBinary, destPath)
 
-	return nil
+	return destPath, nil
 }
 
 func performFileCopy(source, destination string) error {
@@ -410,3 +489,22 @@ func getPlatformExecutableExtension() string {
 	}
 	return ""
 }
+
+// executableExtensionForTarget returns the executable suffix for a target
+// triple, falling back to the host platform when target is empty.
+func executableExtensionForTarget(target string) string {
+	if target == "" {
+		return getPlatformExecutableExtension()
+	}
+	if strings.Contains(target, "windows") {
+		return ".exe"
+	}
+	return ""
+}
+
+func destFilenameForTarget(name, version, target string) string {
+	if target == "" {
+		return fmt.Sprintf("%s-v%s%s", name, version, getPlatformExecutableExtension())
+	}
+	return fmt.Sprintf("%s-v%s-%s%s", name, version, target, executableExtensionForTarget(target))
+}
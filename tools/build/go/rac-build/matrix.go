@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// allSupportedTargets is the preset expanded by -target-all: the handful of
+// desktop triples we actually ship release builds for.
+var allSupportedTargets = []string{
+	"x86_64-pc-windows-gnu",
+	"x86_64-unknown-linux-gnu",
+	"aarch64-apple-darwin",
+	"x86_64-apple-darwin",
+	"aarch64-unknown-linux-gnu",
+}
+
+// resolveTargets returns the set of target triples to build for, or nil for
+// a plain single-host build.
+func resolveTargets(opts BuildOptions) []string {
+	if opts.TargetAll {
+		return allSupportedTargets
+	}
+	return opts.Targets
+}
+
+type targetBuildResult struct {
+	Target string
+	Dest   string
+	Err    error
+}
+
+// runMatrixBuild drives cargo build --target for each of targets, bounded by
+// a worker pool of size buildCtx.Options.Jobs. Every target is attempted
+// independently: a failure on one target does not abort the others, and the
+// successfully built artifacts are still copied into the output directory.
+func runMatrixBuild(ctx context.Context, buildCtx *BuildContext, targets []string) error {
+	jobs := buildCtx.Options.Jobs
+	if jobs <= 0 {
+		jobs = runtime.NumCPU()
+	}
+
+	sem := make(chan struct{}, jobs)
+	results := make(chan targetBuildResult, len(targets))
+
+	var wg sync.WaitGroup
+	for _, target := range targets {
+		target := target
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results <- buildSingleTarget(ctx, buildCtx, target)
+		}()
+	}
+
+	wg.Wait()
+	close(results)
+
+	var succeeded []string
+	var failures []string
+
+	for res := range results {
+		if res.Err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", res.Target, res.Err))
+			log.Printf("✗ FAILED: %s: %v", res.Target, res.Err)
+			continue
+		}
+		succeeded = append(succeeded, res.Dest)
+	}
+
+	log.Printf("%d/%d target(s) succeeded", len(succeeded), len(targets))
+
+	if len(failures) > 0 {
+		return fmt.Errorf("%d/%d target(s) failed: %s", len(failures), len(targets), strings.Join(failures, "; "))
+	}
+
+	return nil
+}
+
+func buildSingleTarget(ctx context.Context, buildCtx *BuildContext, target string) targetBuildResult {
+	log.Printf("Building target %s", target)
+
+	destPath, err := buildTarget(ctx, buildCtx, target)
+	if err != nil {
+		return targetBuildResult{Target: target, Err: err}
+	}
+
+	return targetBuildResult{Target: target, Dest: destPath}
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestResolveTargets(t *testing.T) {
+	if got := resolveTargets(BuildOptions{}); got != nil {
+		t.Fatalf("resolveTargets with no target flags: got %v, want nil", got)
+	}
+
+	if got := resolveTargets(BuildOptions{TargetAll: true}); len(got) != len(allSupportedTargets) {
+		t.Fatalf("resolveTargets with -target-all: got %d targets, want %d", len(got), len(allSupportedTargets))
+	}
+
+	explicit := []string{"x86_64-unknown-linux-gnu"}
+	got := resolveTargets(BuildOptions{Targets: explicit})
+	if len(got) != 1 || got[0] != explicit[0] {
+		t.Fatalf("resolveTargets with explicit -target: got %v, want %v", got, explicit)
+	}
+}
+
+func TestDestFilenameForTarget(t *testing.T) {
+	cases := []struct {
+		name, version, target, want string
+	}{
+		{"rac-clicker", "0.1.0", "", "rac-clicker-v0.1.0" + getPlatformExecutableExtension()},
+		{"rac-clicker", "0.1.0", "x86_64-unknown-linux-gnu", "rac-clicker-v0.1.0-x86_64-unknown-linux-gnu"},
+		{"rac-clicker", "0.1.0", "x86_64-pc-windows-gnu", "rac-clicker-v0.1.0-x86_64-pc-windows-gnu.exe"},
+	}
+
+	for _, c := range cases {
+		if got := destFilenameForTarget(c.name, c.version, c.target); got != c.want {
+			t.Errorf("destFilenameForTarget(%q, %q, %q) = %q, want %q", c.name, c.version, c.target, got, c.want)
+		}
+	}
+}
+
+func TestExecutableExtensionForTarget(t *testing.T) {
+	if got := executableExtensionForTarget("x86_64-pc-windows-gnu"); got != ".exe" {
+		t.Errorf("executableExtensionForTarget(windows target) = %q, want .exe", got)
+	}
+
+	if got := executableExtensionForTarget("x86_64-unknown-linux-gnu"); got != "" {
+		t.Errorf("executableExtensionForTarget(linux target) = %q, want empty", got)
+	}
+}
+
+// writeFakeConcurrencyCargo writes a stand-in for the cargo binary that drops
+// a uniquely-named marker file into markerDir on entry, sleeps briefly, then
+// removes it and fails. It lets TestRunMatrixBuild_RespectsJobsBound observe
+// how many cargo invocations are in flight at once without needing a real
+// toolchain.
+func writeFakeConcurrencyCargo(t *testing.T, dir string) string {
+	t.Helper()
+
+	script := filepath.Join(dir, "fake-cargo.sh")
+	contents := "#!/bin/sh\n" +
+		"marker=\"$MARKER_DIR/$$\"\n" +
+		"touch \"$marker\"\n" +
+		"sleep 0.05\n" +
+		"rm -f \"$marker\"\n" +
+		"exit 1\n"
+
+	if err := os.WriteFile(script, []byte(contents), 0755); err != nil {
+		t.Fatalf("write fake cargo script: %v", err)
+	}
+
+	return script
+}
+
+func TestRunMatrixBuild_RespectsJobsBound(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("no POSIX shell available to drive the fake cargo binary")
+	}
+
+	const jobs = 2
+	targets := []string{"t1", "t2", "t3", "t4", "t5", "t6"}
+
+	markerDir := t.TempDir()
+	fakeCargo := writeFakeConcurrencyCargo(t, t.TempDir())
+	t.Setenv("MARKER_DIR", markerDir)
+
+	buildCtx := &BuildContext{
+		Options:    BuildOptions{Jobs: jobs, NoCache: true},
+		ProjectDir: t.TempDir(),
+		Config:     &CargoConfig{Package: PackageInfo{Name: "rac-clicker", Version: "0.1.0"}},
+		CargoBin:   fakeCargo,
+		Shell:      newShell(t.TempDir(), false, false, false),
+	}
+
+	var maxObserved int32
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			if entries, err := os.ReadDir(markerDir); err == nil {
+				if n := int32(len(entries)); n > atomic.LoadInt32(&maxObserved) {
+					atomic.StoreInt32(&maxObserved, n)
+				}
+			}
+			time.Sleep(2 * time.Millisecond)
+		}
+	}()
+
+	_ = runMatrixBuild(context.Background(), buildCtx, targets)
+	close(done)
+
+	if got := atomic.LoadInt32(&maxObserved); got > jobs {
+		t.Fatalf("observed %d concurrent cargo invocations, want at most %d", got, jobs)
+	}
+}
+
+// writeFakePassFailCargo writes a stand-in for the cargo binary that fails
+// whenever its --target value contains "bad" and otherwise drops a built
+// binary at the location buildTarget expects to find it.
+func writeFakePassFailCargo(t *testing.T, dir string) string {
+	t.Helper()
+
+	script := filepath.Join(dir, "fake-cargo.sh")
+	contents := `#!/bin/sh
+target=""
+while [ $# -gt 0 ]; do
+	if [ "$1" = "--target" ]; then
+		target="$2"
+	fi
+	shift
+done
+
+case "$target" in
+	*bad*) exit 1 ;;
+esac
+
+mkdir -p "target/$target/release"
+touch "target/$target/release/rac-clicker"
+`
+
+	if err := os.WriteFile(script, []byte(contents), 0755); err != nil {
+		t.Fatalf("write fake cargo script: %v", err)
+	}
+
+	return script
+}
+
+func TestRunMatrixBuild_PartialFailureAggregation(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("no POSIX shell available to drive the fake cargo binary")
+	}
+
+	fakeCargo := writeFakePassFailCargo(t, t.TempDir())
+
+	buildCtx := &BuildContext{
+		Options: BuildOptions{
+			Jobs:      2,
+			NoCache:   true,
+			OutputDir: t.TempDir(),
+		},
+		ProjectDir: t.TempDir(),
+		Config:     &CargoConfig{Package: PackageInfo{Name: "rac-clicker", Version: "0.1.0"}},
+		CargoBin:   fakeCargo,
+		Shell:      newShell(t.TempDir(), false, false, false),
+	}
+
+	err := runMatrixBuild(context.Background(), buildCtx, []string{"good-target", "bad-target"})
+	if err == nil {
+		t.Fatalf("runMatrixBuild: expected an error from the failing target")
+	}
+	if !strings.Contains(err.Error(), "bad-target") {
+		t.Fatalf("runMatrixBuild error %q does not mention the failing target", err.Error())
+	}
+	if strings.Contains(err.Error(), "good-target:") {
+		t.Fatalf("runMatrixBuild error %q incorrectly reports the succeeding target as failed", err.Error())
+	}
+
+	destPath := filepath.Join(buildCtx.Options.OutputDir, destFilenameForTarget("rac-clicker", "0.1.0", "good-target"))
+	if !fileExists(destPath) {
+		t.Fatalf("expected the succeeding target's binary to be copied to %s", destPath)
+	}
+}
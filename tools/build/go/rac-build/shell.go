@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Shell centralizes external command execution and file operations so that
+// -x trace output and --dry-run planning render through the same printer
+// instead of the two divergent code paths verbose mode used to take.
+type Shell struct {
+	mu     sync.Mutex
+	Trace  bool
+	DryRun bool
+	JSON   bool
+	Stdout io.Writer
+	Stderr io.Writer
+	dirs   []string // working-directory stack, most recent last
+}
+
+func newShell(rootDir string, trace, dryRun, jsonMode bool) *Shell {
+	return &Shell{
+		Trace:  trace,
+		DryRun: dryRun,
+		JSON:   jsonMode,
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+		dirs:   []string{rootDir},
+	}
+}
+
+// ShowCmd prints a shell-equivalent, argv-quoted command line whenever
+// tracing or dry-run planning is active. It is the single place both modes
+// render output through, so a command is printed the same way under `-x`
+// and `--dry-run`. Steps a dry run skips (storing into and trimming the
+// build cache) are printed only under `-x`.
+func (s *Shell) ShowCmd(dir, format string, args ...interface{}) {
+	if !s.Trace && !s.DryRun {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cmd := fmt.Sprintf(format, args...)
+
+	current := ""
+	if len(s.dirs) > 0 {
+		current = s.dirs[len(s.dirs)-1]
+	}
+
+	if dir != "" && dir != current {
+		cmd = fmt.Sprintf("cd %s && %s", shellQuote(dir), cmd)
+	}
+
+	fmt.Fprintln(s.Stderr, cmd)
+}
+
+// Chdir changes the process working directory, pushing it onto the
+// directory stack so later ShowCmd calls know whether a `cd` prefix is
+// needed.
+func (s *Shell) Chdir(dir string) error {
+	s.ShowCmd("", "cd %s", shellQuote(dir))
+
+	if err := os.Chdir(dir); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.dirs = append(s.dirs, dir)
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Run prints the shell-equivalent command line for name/args running in dir,
+// then executes it unless the shell is in dry-run mode. Output is streamed
+// line by line: in --json mode each line becomes a CargoOutput event, in
+// --verbose mode it is passed through to Stdout/Stderr, and otherwise it is
+// captured so it can be printed on failure. target is stamped onto the
+// CargoStart/CargoOutput/CargoEnd events so a --json consumer can attribute
+// output when matrix builds run several of these concurrently; pass "" for
+// single-target invocations.
+func (s *Shell) Run(ctx context.Context, dir string, verbose bool, target, name string, args ...string) error {
+	s.ShowCmd(dir, "%s %s", shellQuote(name), shellQuoteArgs(args))
+
+	if s.DryRun {
+		return nil
+	}
+
+	emitEvent(s.JSON, cargoStartEvent{Event: "CargoStart", Target: target, Args: args})
+	start := time.Now()
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+
+	stdoutPipe, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("create stdout pipe: %w", err)
+	}
+
+	stderrPipe, err := cmd.StderrPipe()
+	if err != nil {
+		return fmt.Errorf("create stderr pipe: %w", err)
+	}
+
+	var captured strings.Builder
+	var capturedMu sync.Mutex
+
+	streamOutput := func(wg *sync.WaitGroup, r io.Reader, stream string, out io.Writer) {
+		defer wg.Done()
+
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+		for scanner.Scan() {
+			line := scanner.Text()
+
+			switch {
+			case s.JSON:
+				emitEvent(true, cargoOutputEvent{Event: "CargoOutput", Target: target, Stream: stream, Data: line})
+			case verbose:
+				fmt.Fprintln(out, line)
+			default:
+				capturedMu.Lock()
+				captured.WriteString(line)
+				captured.WriteByte('\n')
+				capturedMu.Unlock()
+			}
+		}
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start command: %w", err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go streamOutput(&wg, stdoutPipe, "stdout", s.Stdout)
+	go streamOutput(&wg, stderrPipe, "stderr", s.Stderr)
+	wg.Wait()
+
+	runErr := cmd.Wait()
+
+	exitCode := 0
+	if runErr != nil {
+		exitCode = 1
+		var exitErr *exec.ExitError
+		if errors.As(runErr, &exitErr) {
+			exitCode = exitErr.ExitCode()
+		}
+	}
+
+	emitEvent(s.JSON, cargoEndEvent{Event: "CargoEnd", Target: target, ExitCode: exitCode, DurationMs: time.Since(start).Milliseconds()})
+
+	if runErr != nil {
+		if !verbose && !s.JSON && captured.Len() > 0 {
+			fmt.Fprintln(s.Stderr, captured.String())
+		}
+		return runErr
+	}
+
+	return nil
+}
+
+// CopyFile copies source to destination via an atomic temp-file + rename,
+// printing the equivalent `cp` line first.
+func (s *Shell) CopyFile(source, destination string) error {
+	s.ShowCmd("", "cp %s %s", shellQuote(source), shellQuote(destination))
+
+	if s.DryRun {
+		return nil
+	}
+
+	return performFileCopy(source, destination)
+}
+
+// RemoveAll recursively deletes path, printing the equivalent `rm -rf` line
+// first.
+func (s *Shell) RemoveAll(path string) error {
+	s.ShowCmd("", "rm -rf %s", shellQuote(path))
+
+	if s.DryRun {
+		return nil
+	}
+
+	return os.RemoveAll(path)
+}
+
+// RunCaptured executes name/args to completion and returns its combined
+// stdout/stderr, printing the shell-equivalent command line first. Unlike
+// Run, it doesn't stream output or emit CargoOutput events — it's for
+// short-lived commands like signature verification where the caller just
+// wants the result.
+func (s *Shell) RunCaptured(name string, args ...string) (string, error) {
+	s.ShowCmd("", "%s %s", shellQuote(name), shellQuoteArgs(args))
+
+	if s.DryRun {
+		return "", nil
+	}
+
+	output, err := exec.Command(name, args...).CombinedOutput()
+	return string(output), err
+}
+
+func shellQuoteArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = shellQuote(a)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// shellQuote quotes s the way a POSIX shell would need it quoted to be
+// passed through unmodified, leaving already-safe tokens bare.
+func shellQuote(s string) string {
+	if s == "" {
+		return "''"
+	}
+
+	for _, r := range s {
+		safe := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') ||
+			r == '-' || r == '_' || r == '.' || r == '/' || r == ':' || r == '='
+		if !safe {
+			return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+		}
+	}
+
+	return s
+}
@@ -0,0 +1,386 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const sha256SumsFilename = "SHA256SUMS"
+
+// recordArtifact appends path to the set of binaries produced by this run,
+// guarded for concurrent matrix builds.
+func (b *BuildContext) recordArtifact(path string) {
+	b.artifactsMu.Lock()
+	b.Artifacts = append(b.Artifacts, path)
+	b.artifactsMu.Unlock()
+}
+
+// runSignRelease writes per-artifact .sha256/.sha512 checksums, an aggregate
+// SHA256SUMS file, and optional minisign/cosign signatures for every binary
+// produced by this run.
+func runSignRelease(buildCtx *BuildContext) error {
+	if len(buildCtx.Artifacts) == 0 {
+		return nil
+	}
+
+	artifacts := append([]string(nil), buildCtx.Artifacts...)
+	sort.Strings(artifacts)
+
+	sumsPath := filepath.Join(filepath.Dir(artifacts[0]), sha256SumsFilename)
+
+	for _, artifact := range artifacts {
+		buildCtx.Shell.ShowCmd("", "sha256sum %s > %s", shellQuote(artifact), shellQuote(artifact+".sha256"))
+		buildCtx.Shell.ShowCmd("", "sha512sum %s > %s", shellQuote(artifact), shellQuote(artifact+".sha512"))
+
+		if buildCtx.Options.SignMinisignKey != "" {
+			buildCtx.Shell.ShowCmd("", "minisign -S -s %s -m %s", shellQuote(buildCtx.Options.SignMinisignKey), shellQuote(artifact))
+		}
+
+		if buildCtx.Options.SignCosignKeyRef != "" {
+			buildCtx.Shell.ShowCmd("", "cosign sign-blob --key %s --output-signature %s --yes %s",
+				shellQuote(buildCtx.Options.SignCosignKeyRef), shellQuote(artifact+".sig"), shellQuote(artifact))
+		}
+	}
+	buildCtx.Shell.ShowCmd("", "cat *.sha256 > %s", shellQuote(sumsPath))
+
+	if buildCtx.Options.DryRun {
+		return nil
+	}
+
+	sumLines := make([]string, 0, len(artifacts))
+
+	for _, artifact := range artifacts {
+		sum256, err := writeChecksumFile(artifact, sha256.New(), ".sha256")
+		if err != nil {
+			return fmt.Errorf("write sha256 for %s: %w", artifact, err)
+		}
+
+		if _, err := writeChecksumFile(artifact, sha512.New(), ".sha512"); err != nil {
+			return fmt.Errorf("write sha512 for %s: %w", artifact, err)
+		}
+
+		sumLines = append(sumLines, fmt.Sprintf("%s  %s", sum256, filepath.Base(artifact)))
+
+		if buildCtx.Options.SignMinisignKey != "" {
+			if err := signWithMinisign(buildCtx, artifact); err != nil {
+				return fmt.Errorf("minisign %s: %w", artifact, err)
+			}
+		}
+
+		if buildCtx.Options.SignCosignKeyRef != "" {
+			if err := signWithCosign(buildCtx, artifact); err != nil {
+				return fmt.Errorf("cosign %s: %w", artifact, err)
+			}
+		}
+	}
+
+	if err := os.WriteFile(sumsPath, []byte(strings.Join(sumLines, "\n")+"\n"), 0644); err != nil {
+		return fmt.Errorf("write %s: %w", sha256SumsFilename, err)
+	}
+
+	log.Printf("✓ Wrote %s covering %d artifact(s)", sumsPath, len(artifacts))
+
+	return nil
+}
+
+// writeChecksumFile hashes path with h and writes "<hex>  <basename>\n" to
+// path+ext using the atomic temp-file + rename pattern performFileCopy uses.
+func writeChecksumFile(path string, h hash.Hash, ext string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("open %s: %w", path, err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("hash %s: %w", path, err)
+	}
+
+	sum := fmt.Sprintf("%x", h.Sum(nil))
+	line := fmt.Sprintf("%s  %s\n", sum, filepath.Base(path))
+
+	destPath := path + ext
+	tempPath := destPath + tempFileSuffix
+
+	if err := os.WriteFile(tempPath, []byte(line), 0644); err != nil {
+		return "", fmt.Errorf("write temp checksum: %w", err)
+	}
+
+	if err := os.Rename(tempPath, destPath); err != nil {
+		return "", fmt.Errorf("rename checksum file: %w", err)
+	}
+
+	return sum, nil
+}
+
+// validateSigningTool resolves name on PATH so callers fail with a clear
+// error up front instead of a confusing exec failure mid-release.
+func validateSigningTool(name string) (string, error) {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return "", fmt.Errorf("%s not found in PATH: %w", name, err)
+	}
+	return path, nil
+}
+
+func signWithMinisign(buildCtx *BuildContext, artifact string) error {
+	bin, err := validateSigningTool("minisign")
+	if err != nil {
+		return err
+	}
+
+	args := []string{"-S", "-s", buildCtx.Options.SignMinisignKey, "-m", artifact}
+
+	return runSigningCommand(buildCtx, bin, args)
+}
+
+func signWithCosign(buildCtx *BuildContext, artifact string) error {
+	bin, err := validateSigningTool("cosign")
+	if err != nil {
+		return err
+	}
+
+	args := []string{
+		"sign-blob",
+		"--key", buildCtx.Options.SignCosignKeyRef,
+		"--output-signature", artifact + ".sig",
+		"--yes",
+		artifact,
+	}
+
+	return runSigningCommand(buildCtx, bin, args)
+}
+
+// runSigningCommand shells out to a signing tool, feeding it the configured
+// passphrase over stdin so CI can sign without a TTY. Its shell-equivalent
+// command line is printed by runSignRelease, not here.
+func runSigningCommand(buildCtx *BuildContext, bin string, args []string) error {
+	if buildCtx.Options.DryRun {
+		return nil
+	}
+
+	cmd := exec.CommandContext(context.Background(), bin, args...)
+
+	if buildCtx.Options.SignPassphraseFile != "" {
+		passphrase, err := os.ReadFile(buildCtx.Options.SignPassphraseFile)
+		if err != nil {
+			return fmt.Errorf("read passphrase file: %w", err)
+		}
+		cmd.Stdin = strings.NewReader(strings.TrimRight(string(passphrase), "\n") + "\n")
+	}
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s: %w: %s", bin, err, strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+// runVerifyRelease re-reads every artifact in the output directory,
+// recomputes its checksums against the .sha256/.sha512 sidecars, and
+// validates minisign/cosign signatures against the matching public key —
+// the audit-side counterpart to runSignRelease.
+func runVerifyRelease(buildCtx *BuildContext) error {
+	outputDir := releaseTargetDir
+	if buildCtx.Options.OutputDir != "" {
+		outputDir = buildCtx.Options.OutputDir
+	}
+
+	absOutputDir, err := filepath.Abs(outputDir)
+	if err != nil {
+		return fmt.Errorf("resolve output directory: %w", err)
+	}
+
+	entries, err := os.ReadDir(absOutputDir)
+	if err != nil {
+		return fmt.Errorf("read output directory: %w", err)
+	}
+
+	var failures []string
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || isSidecarFile(name) {
+			continue
+		}
+
+		if !isReleaseArtifactName(name, buildCtx.Config.Package.Name, buildCtx.Config.Package.Version) {
+			continue
+		}
+
+		artifact := filepath.Join(absOutputDir, name)
+
+		if err := verifyArtifact(buildCtx, artifact); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+
+		log.Printf("✓ VERIFIED: %s", name)
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("%d artifact(s) failed verification: %s", len(failures), strings.Join(failures, "; "))
+	}
+
+	return nil
+}
+
+// isReleaseArtifactName reports whether name matches the
+// destFilenameForTarget naming pattern ("<name>-v<version>[-<target>][.exe]")
+// for this release. Verification only considers files this pipeline could
+// plausibly have produced itself — anything else in the output directory
+// (cargo's own build byproducts, stray files left over from a prior run at a
+// default output directory shared with cargo's own target/release) is left
+// alone rather than reported as verified.
+func isReleaseArtifactName(name, pkgName, version string) bool {
+	prefix := fmt.Sprintf("%s-v%s", pkgName, version)
+	if !strings.HasPrefix(name, prefix) {
+		return false
+	}
+
+	rest := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ".exe")
+	return rest == "" || strings.HasPrefix(rest, "-")
+}
+
+func isSidecarFile(name string) bool {
+	if name == sha256SumsFilename {
+		return true
+	}
+	for _, ext := range []string{".sha256", ".sha512", ".minisig", ".sig", tempFileSuffix} {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+func verifyArtifact(buildCtx *BuildContext, artifact string) error {
+	if err := verifyChecksumSidecar(artifact, ".sha256", sha256.New()); err != nil {
+		return err
+	}
+
+	if err := verifyChecksumSidecar(artifact, ".sha512", sha512.New()); err != nil {
+		return err
+	}
+
+	if err := verifyMinisignSignature(buildCtx, artifact); err != nil {
+		return err
+	}
+
+	if err := verifyCosignSignature(buildCtx, artifact); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// verifyChecksumSidecar is a no-op when no sidecar of this kind exists,
+// since a release may only have been checksummed with one algorithm.
+func verifyChecksumSidecar(artifact, ext string, h hash.Hash) error {
+	sidecar := artifact + ext
+	if !fileExists(sidecar) {
+		return nil
+	}
+
+	expected, err := readChecksumLine(sidecar)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(artifact)
+	if err != nil {
+		return fmt.Errorf("open artifact: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	if _, err := io.Copy(h, f); err != nil {
+		return fmt.Errorf("hash artifact: %w", err)
+	}
+
+	actual := fmt.Sprintf("%x", h.Sum(nil))
+	if actual != expected {
+		return fmt.Errorf("%s checksum mismatch (expected %s, got %s)", ext, expected, actual)
+	}
+
+	return nil
+}
+
+func readChecksumLine(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("open %s: %w", path, err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() {
+		return "", fmt.Errorf("empty checksum file %s", path)
+	}
+
+	fields := strings.Fields(scanner.Text())
+	if len(fields) == 0 {
+		return "", fmt.Errorf("malformed checksum file %s", path)
+	}
+
+	return fields[0], nil
+}
+
+func verifyMinisignSignature(buildCtx *BuildContext, artifact string) error {
+	sigPath := artifact + ".minisig"
+	if !fileExists(sigPath) || buildCtx.Options.VerifyMinisignPubkey == "" {
+		return nil
+	}
+
+	bin, err := validateSigningTool("minisign")
+	if err != nil {
+		return err
+	}
+
+	args := []string{"-V", "-p", buildCtx.Options.VerifyMinisignPubkey, "-m", artifact}
+
+	output, err := buildCtx.Shell.RunCaptured(bin, args...)
+	if err != nil {
+		return fmt.Errorf("minisign verify failed: %w: %s", err, strings.TrimSpace(output))
+	}
+
+	return nil
+}
+
+func verifyCosignSignature(buildCtx *BuildContext, artifact string) error {
+	sigPath := artifact + ".sig"
+	if !fileExists(sigPath) || buildCtx.Options.VerifyCosignPubkey == "" {
+		return nil
+	}
+
+	bin, err := validateSigningTool("cosign")
+	if err != nil {
+		return err
+	}
+
+	args := []string{"verify-blob", "--key", buildCtx.Options.VerifyCosignPubkey, "--signature", sigPath, artifact}
+
+	output, err := buildCtx.Shell.RunCaptured(bin, args...)
+	if err != nil {
+		return fmt.Errorf("cosign verify failed: %w: %s", err, strings.TrimSpace(output))
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"crypto/sha256"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteAndVerifyChecksumSidecar_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	artifact := filepath.Join(dir, "rac-clicker-v0.1.0")
+
+	if err := os.WriteFile(artifact, []byte("pretend-binary-contents"), 0644); err != nil {
+		t.Fatalf("write artifact: %v", err)
+	}
+
+	if _, err := writeChecksumFile(artifact, sha256.New(), ".sha256"); err != nil {
+		t.Fatalf("writeChecksumFile: %v", err)
+	}
+
+	if err := verifyChecksumSidecar(artifact, ".sha256", sha256.New()); err != nil {
+		t.Fatalf("verifyChecksumSidecar: %v", err)
+	}
+
+	if err := os.WriteFile(artifact, []byte("mutated-binary-contents"), 0644); err != nil {
+		t.Fatalf("mutate artifact: %v", err)
+	}
+
+	if err := verifyChecksumSidecar(artifact, ".sha256", sha256.New()); err == nil {
+		t.Fatalf("verifyChecksumSidecar: expected a mismatch after mutating the artifact")
+	}
+}
+
+// TestMinisignSignAndVerify_FixtureKeyPair generates a throwaway minisign key
+// pair and round-trips a real sign + verify through signWithMinisign and
+// verifyMinisignSignature. It skips when minisign isn't on PATH, since CI
+// images vary in whether they carry it.
+func TestMinisignSignAndVerify_FixtureKeyPair(t *testing.T) {
+	minisignBin, err := exec.LookPath("minisign")
+	if err != nil {
+		t.Skip("minisign not found in PATH, skipping fixture key pair round trip")
+	}
+
+	dir := t.TempDir()
+	secretKey := filepath.Join(dir, "fixture.key")
+	publicKey := filepath.Join(dir, "fixture.pub")
+
+	if output, err := exec.Command(minisignBin, "-G", "-s", secretKey, "-p", publicKey, "-W").CombinedOutput(); err != nil {
+		t.Fatalf("generate fixture key pair: %v: %s", err, output)
+	}
+
+	artifact := filepath.Join(dir, "rac-clicker-v0.1.0")
+	if err := os.WriteFile(artifact, []byte("pretend-binary-contents"), 0644); err != nil {
+		t.Fatalf("write artifact: %v", err)
+	}
+
+	signCtx := &BuildContext{
+		Options: BuildOptions{SignMinisignKey: secretKey},
+		Shell:   newShell(dir, false, false, false),
+	}
+
+	if err := signWithMinisign(signCtx, artifact); err != nil {
+		t.Fatalf("signWithMinisign: %v", err)
+	}
+
+	verifyCtx := &BuildContext{
+		Options: BuildOptions{VerifyMinisignPubkey: publicKey},
+		Shell:   newShell(dir, false, false, false),
+	}
+
+	if err := verifyMinisignSignature(verifyCtx, artifact); err != nil {
+		t.Fatalf("verifyMinisignSignature: %v", err)
+	}
+
+	verifyCtx.Options.VerifyMinisignPubkey = ""
+	wrongKeyDir := t.TempDir()
+	wrongPublicKey := filepath.Join(wrongKeyDir, "wrong.pub")
+	if output, err := exec.Command(minisignBin, "-G", "-s", filepath.Join(wrongKeyDir, "wrong.key"), "-p", wrongPublicKey, "-W").CombinedOutput(); err != nil {
+		t.Fatalf("generate second fixture key pair: %v: %s", err, output)
+	}
+
+	mismatchCtx := &BuildContext{
+		Options: BuildOptions{VerifyMinisignPubkey: wrongPublicKey},
+		Shell:   newShell(dir, false, false, false),
+	}
+
+	if err := verifyMinisignSignature(mismatchCtx, artifact); err == nil {
+		t.Fatalf("verifyMinisignSignature: expected failure when verifying against the wrong public key")
+	}
+}